@@ -0,0 +1,8 @@
+package s3
+
+// Backend is an alias for Client, used when a value's role as a pluggable storage backend
+// (e.g. a fake used in unit tests) is more relevant than its role as a live AWS session. Any
+// backend, including the AWS-backed s3Client, the in-memory driver returned by
+// NewInMemoryClient, and the gofakes3-backed driver returned by NewLocalClient, can stand in
+// for each other behind this interface.
+type Backend = Client
@@ -0,0 +1,316 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// ClientCtx mirrors Client but threads a context.Context through every call, so callers can
+// cancel or time out long-running S3 operations the way Vault's S3 physical backend does. It's
+// a separate interface (rather than changing Client) so existing callers of Client aren't forced
+// to adopt contexts. s3Client implements every one of its methods by converting itself to
+// s3ClientCtx and calling the Ctx-aware method with context.Background(), or with the caller's
+// ctx for Walk (see s3.go and the *Ctx-adjacent methods in list.go, stream.go, presign.go, and
+// encryption.go), so
+// the two interfaces can't drift apart on method coverage again.
+type ClientCtx interface {
+	ListDirectories(ctx context.Context, p Path) ([]Path, error)
+	List(ctx context.Context, p Path) ([]Path, error)
+	ListAll(ctx context.Context, p Path) ([]Path, error)
+	ListPaged(ctx context.Context, p Path, opts ListOptions) ([]Path, error)
+	Walk(ctx context.Context, p Path) (<-chan Path, <-chan error)
+	Download(ctx context.Context, p Path, opts ...EncryptionOption) ([]byte, error)
+	DownloadInFile(ctx context.Context, p Path, f *os.File) (int64, error)
+	DownloadStream(ctx context.Context, p Path, w io.WriterAt, opts ...EncryptionOption) (int64, error)
+	PresignGet(ctx context.Context, p Path, expiry time.Duration) (string, error)
+	PresignPut(ctx context.Context, p Path, expiry time.Duration, opts ...PresignOption) (string, error)
+	Copy(ctx context.Context, source Path, target Path, opts ...EncryptionOption) error
+	Upload(ctx context.Context, b []byte, p Path, opts ...EncryptionOption) error
+	UploadStream(ctx context.Context, r io.Reader, p Path, opts ...UploadOption) error
+	AbortMultipartUpload(ctx context.Context, p Path, uploadID string) error
+	DeleteRecursive(ctx context.Context, p Path) error
+	DeleteBucket(ctx context.Context, name string, log logr.Logger) error
+	DeleteObject(ctx context.Context, p Path) error
+	Exists(ctx context.Context, p Path) bool
+	CreateBucket(ctx context.Context, name string) error
+	AddBucketTag(ctx context.Context, bucketName string, tags map[string]string, overwrite bool) error
+	BlockBucketPublicAccess(ctx context.Context, name string) error
+	BucketServerSideEncryption(ctx context.Context, bucket string) error
+	Stat(ctx context.Context, p Path, opts ...EncryptionOption) (ObjectInfo, error)
+}
+
+// WithHTTPClient overrides the HTTP client used for requests, so callers can inject a
+// cleanhttp-style client configured with their own timeouts and connection pool.
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *aws.Config) {
+		c.HTTPClient = hc
+	}
+}
+
+// s3ClientCtx is the ClientCtx counterpart to s3Client, calling the *WithContext variant of
+// each SDK method so cancellation and deadlines propagate down to the underlying HTTP request.
+// It has the exact same field layout as s3Client so the two can be converted between each other,
+// which is how s3Client's methods delegate into the Ctx-aware implementations below.
+type s3ClientCtx struct {
+	client  *s3.S3
+	session *session.Session
+}
+
+// NewClientCtx initializes a ClientCtx backed by a new session, in the same way NewClient does
+// for Client.
+func NewClientCtx(options ...ClientOption) (ClientCtx, error) {
+	config := &aws.Config{
+		Region: aws.String(defaultRegion),
+	}
+	for _, option := range options {
+		option(config)
+	}
+	sess, err := session.NewSession(config)
+	if err != nil {
+		return &s3ClientCtx{}, err
+	}
+	return &s3ClientCtx{client: s3.New(sess), session: sess}, nil
+}
+
+func (s3C s3ClientCtx) listBase(ctx context.Context, p Path) (*s3.ListObjectsV2Output, error) {
+	return s3C.client.ListObjectsV2WithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket:    aws.String(p.Bucket),
+		Prefix:    aws.String(listPrefix(p.Key)),
+		Delimiter: aws.String("/"),
+	})
+}
+
+// ListDirectories gets the subdirectories of this path. See Client.ListDirectories.
+func (s3C s3ClientCtx) ListDirectories(ctx context.Context, p Path) ([]Path, error) {
+	resp, err := s3C.listBase(ctx, p)
+	if err != nil {
+		return []Path{}, err
+	}
+
+	s3Paths := make([]Path, len(resp.CommonPrefixes))
+	for i, cp := range resp.CommonPrefixes {
+		s3Paths[i] = Path{Bucket: p.Bucket, Key: *cp.Prefix}
+	}
+	return s3Paths, nil
+}
+
+// List returns a list of Paths under the passed Path. See Client.List.
+func (s3C s3ClientCtx) List(ctx context.Context, p Path) ([]Path, error) {
+	resp, err := s3C.listBase(ctx, p)
+	if err != nil {
+		return []Path{}, err
+	}
+
+	s3Paths := make([]Path, len(resp.Contents))
+	for i, content := range resp.Contents {
+		s3Paths[i] = Path{Bucket: p.Bucket, Key: *content.Key}
+	}
+	return s3Paths, nil
+}
+
+// Download gets bytes from S3 Path. See Client.Download.
+func (s3C s3ClientCtx) Download(ctx context.Context, p Path, opts ...EncryptionOption) ([]byte, error) {
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(p.Bucket),
+		Key:    aws.String(p.Key),
+	}
+	applyEncryptionOptions(opts).applyToGet(input)
+
+	resp, err := s3C.client.GetObjectWithContext(ctx, input)
+	if err != nil {
+		return []byte{}, err
+	}
+	defer resp.Body.Close()
+
+	buf := bytes.Buffer{}
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		return []byte{}, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DownloadInFile downloads the s3 path object into the provided file. See Client.DownloadInFile.
+func (s3C s3ClientCtx) DownloadInFile(ctx context.Context, p Path, f *os.File) (int64, error) {
+	downloader := s3manager.NewDownloader(s3C.session)
+	return downloader.DownloadWithContext(ctx, f, &s3.GetObjectInput{
+		Bucket: aws.String(p.Bucket),
+		Key:    aws.String(p.Key),
+	})
+}
+
+// Copy copies resources from source Path to target Path. See Client.Copy.
+func (s3C s3ClientCtx) Copy(ctx context.Context, source Path, target Path, opts ...EncryptionOption) error {
+	input := &s3.CopyObjectInput{
+		Bucket:     aws.String(target.Bucket),
+		Key:        aws.String(target.Key),
+		CopySource: aws.String(source.Join()),
+	}
+	cfg := applyEncryptionOptions(opts)
+	cfg.applyToCopy(input)
+	cfg.applyToCopySource(input)
+
+	_, err := s3C.client.CopyObjectWithContext(ctx, input)
+	return err
+}
+
+// Upload writes byte array to S3 Path location. See Client.Upload.
+func (s3C s3ClientCtx) Upload(ctx context.Context, b []byte, p Path, opts ...EncryptionOption) error {
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(p.Bucket),
+		Key:    aws.String(p.Key),
+		Body:   bytes.NewReader(b),
+	}
+	applyEncryptionOptions(opts).applyToPut(input)
+
+	_, err := s3C.client.PutObjectWithContext(ctx, input)
+	return err
+}
+
+// DeleteRecursive removes resources under the S3 Path recursively. See Client.DeleteRecursive.
+func (s3C s3ClientCtx) DeleteRecursive(ctx context.Context, p Path) error {
+	iter := s3manager.NewDeleteListIterator(s3C.client, &s3.ListObjectsInput{
+		Bucket: aws.String(p.Bucket),
+		Prefix: aws.String(listPrefix(p.Key)),
+	})
+	return s3manager.NewBatchDeleteWithClient(s3C.client).Delete(ctx, iter)
+}
+
+// DeleteBucket removes the S3 Bucket. See Client.DeleteBucket.
+func (s3C s3ClientCtx) DeleteBucket(ctx context.Context, name string, log logr.Logger) error {
+	iter := s3manager.NewDeleteListIterator(s3C.client, &s3.ListObjectsInput{
+		Bucket: aws.String(name),
+	})
+	if err := s3manager.NewBatchDeleteWithClient(s3C.client).Delete(ctx, iter); err != nil {
+		log.Error(err, "unable to remove objects from bucket for deletion", "bucket", name)
+		return err
+	}
+	log.Info("removed all object(s) from bucket for deletion", "bucket", name)
+
+	_, err := s3C.client.DeleteBucketWithContext(ctx, &s3.DeleteBucketInput{Bucket: aws.String(name)})
+	return err
+}
+
+// DeleteObject removes a single S3 object. See Client.DeleteObject.
+func (s3C s3ClientCtx) DeleteObject(ctx context.Context, p Path) error {
+	_, err := s3C.client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(p.Bucket),
+		Key:    aws.String(p.Key),
+	})
+	return err
+}
+
+// Exists returns true if the object metadata exists, false otherwise. See Client.Exists.
+func (s3C s3ClientCtx) Exists(ctx context.Context, p Path) bool {
+	_, err := s3C.client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(p.Bucket),
+		Key:    aws.String(p.Key),
+	})
+	return err == nil
+}
+
+// CreateBucket creates a new bucket. See Client.CreateBucket.
+func (s3C s3ClientCtx) CreateBucket(ctx context.Context, name string) error {
+	_, err := s3C.client.CreateBucketWithContext(ctx, &s3.CreateBucketInput{
+		Bucket: aws.String(name),
+	})
+	return err
+}
+
+// AddBucketTag adds a tag to the bucket metadata. See Client.AddBucketTag.
+func (s3C s3ClientCtx) AddBucketTag(ctx context.Context, bucketName string, tags map[string]string, overwrite bool) error {
+	if getTagOutput, err := s3C.client.GetBucketTaggingWithContext(
+		ctx,
+		&s3.GetBucketTaggingInput{
+			Bucket: aws.String(bucketName),
+		},
+	); err != nil {
+		// Handling special error code
+		// * Error code: NoSuchTagSetError Description: There is no tag set associated with the bucket.
+		if awsErr, ok := err.(awserr.Error); ok {
+			errCode := awsErr.Code()
+			switch errCode {
+			case "NoSuchTagSet":
+				break
+			default:
+				return awsErr
+			}
+		} else {
+			return err
+		}
+	} else {
+		for _, tag := range getTagOutput.TagSet {
+			// Skip tags present in the list
+			if tag.Key == nil || tag.Value == nil {
+				// Defensive measure, prevent nil tags
+				continue
+			}
+			if _, ok := tags[*tag.Key]; ok && overwrite {
+				// overwrite
+				continue
+			}
+			tags[*tag.Key] = *tag.Value
+		}
+	}
+
+	tagSet := []*s3.Tag{}
+	for key, value := range tags {
+		tagSet = append(tagSet, &s3.Tag{
+			Key:   aws.String(key),
+			Value: aws.String(value),
+		})
+	}
+
+	_, err := s3C.client.PutBucketTaggingWithContext(
+		ctx,
+		&s3.PutBucketTaggingInput{
+			Bucket: aws.String(bucketName),
+			Tagging: &s3.Tagging{
+				TagSet: tagSet,
+			},
+		})
+	return err
+}
+
+// BlockBucketPublicAccess blocks public access for a named bucket. See
+// Client.BlockBucketPublicAccess.
+func (s3C s3ClientCtx) BlockBucketPublicAccess(ctx context.Context, name string) error {
+	_, err := s3C.client.PutPublicAccessBlockWithContext(ctx, &s3.PutPublicAccessBlockInput{
+		Bucket: aws.String(name),
+		PublicAccessBlockConfiguration: &s3.PublicAccessBlockConfiguration{
+			BlockPublicAcls:       aws.Bool(true),
+			BlockPublicPolicy:     aws.Bool(true),
+			IgnorePublicAcls:      aws.Bool(true),
+			RestrictPublicBuckets: aws.Bool(true),
+		},
+	})
+	return err
+}
+
+// BucketServerSideEncryption sets the bucket's default encryption to aws:kms. See
+// Client.BucketServerSideEncryption.
+func (s3C s3ClientCtx) BucketServerSideEncryption(ctx context.Context, bucket string) error {
+	bucketKeyEnabled := true
+	defEnc := &s3.ServerSideEncryptionByDefault{SSEAlgorithm: aws.String(s3.ServerSideEncryptionAwsKms)}
+	rule := &s3.ServerSideEncryptionRule{
+		ApplyServerSideEncryptionByDefault: defEnc,
+		BucketKeyEnabled:                   &bucketKeyEnabled,
+	}
+	rules := []*s3.ServerSideEncryptionRule{rule}
+	serverConfig := &s3.ServerSideEncryptionConfiguration{Rules: rules}
+	input := &s3.PutBucketEncryptionInput{Bucket: aws.String(bucket), ServerSideEncryptionConfiguration: serverConfig}
+
+	_, err := s3C.client.PutBucketEncryptionWithContext(ctx, input)
+	return err
+}
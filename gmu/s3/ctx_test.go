@@ -0,0 +1,52 @@
+package s3_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PrimerAI/go-micro-utils-public/gmu/s3"
+	"github.com/PrimerAI/go-micro-utils-public/gmu/s3/s3test"
+)
+
+// TestClientCtxRoundTrip exercises NewClientCtx against the same gofakes3 server s3test.NewLocalClient
+// stands up for Client, so ClientCtx is checked against a real (if fake) S3-compatible endpoint
+// rather than just compiling against the interface.
+func TestClientCtxRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	local, err := s3test.NewLocalClient()
+	if err != nil {
+		t.Fatalf("NewLocalClient: %v", err)
+	}
+	defer local.Close()
+
+	client, err := s3.NewClientCtx(
+		s3.WithEndpoint(local.Endpoint()),
+		s3.WithS3ForcePathStyle(true),
+		s3.WithDisableSSL(true),
+		s3.WithCredentials(credentials.NewStaticCredentials("FAKE_ACCESS_KEY", "FAKE_SECRET_KEY", "")),
+	)
+	assert.NoError(err)
+
+	ctx := context.Background()
+	p := s3.Path{Bucket: "test-bucket", Key: "dir/object.txt"}
+	assert.NoError(client.CreateBucket(ctx, p.Bucket))
+
+	want := []byte("hello ctx")
+	assert.NoError(client.Upload(ctx, want, p))
+	assert.True(client.Exists(ctx, p))
+
+	got, err := client.Download(ctx, p)
+	assert.NoError(err)
+	assert.Equal(want, got)
+
+	listed, err := client.ListAll(ctx, s3.Path{Bucket: p.Bucket, Key: "dir"})
+	assert.NoError(err)
+	assert.Len(listed, 1)
+
+	assert.NoError(client.DeleteRecursive(ctx, s3.Path{Bucket: p.Bucket, Key: "dir"}))
+	assert.False(client.Exists(ctx, p))
+}
@@ -0,0 +1,224 @@
+package s3
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// encryptionConfig holds the server-side encryption settings gathered from a chain of
+// EncryptionOptions, to be applied to the relevant SDK input type for the operation at hand.
+type encryptionConfig struct {
+	algorithm      string
+	kmsKeyID       string
+	sseCustomerKey []byte
+
+	// sourceSSECustomerKey is only used by Copy, to decrypt an SSE-C source object being read
+	// as part of the copy. It's kept separate from sseCustomerKey, which (when set) encrypts
+	// the destination object instead.
+	sourceSSECustomerKey []byte
+}
+
+// sseCustomerHeaders returns the SSECustomerAlgorithm/Key/KeyMD5 triple S3 expects whenever an
+// SSE-C key is supplied, either to encrypt a PUT or to decrypt a GET/HEAD/copy-source read.
+func sseCustomerHeaders(key []byte) (algorithm, customerKey, keyMD5 string) {
+	sum := md5.Sum(key)
+	return s3.ServerSideEncryptionAes256, string(key), base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// EncryptionOption configures the server-side encryption of an individual object, overriding
+// any bucket-level default set by BucketServerSideEncryption.
+type EncryptionOption func(*encryptionConfig)
+
+// WithSSEAES256 encrypts the object with SSE-S3 (AES256), using keys managed entirely by S3.
+func WithSSEAES256() EncryptionOption {
+	return func(c *encryptionConfig) {
+		c.algorithm = s3.ServerSideEncryptionAes256
+	}
+}
+
+// WithSSEKMS encrypts the object with SSE-KMS, using the given customer master key ID. An
+// empty keyID leaves the object encrypted with the account's default KMS key.
+func WithSSEKMS(keyID string) EncryptionOption {
+	return func(c *encryptionConfig) {
+		c.algorithm = s3.ServerSideEncryptionAwsKms
+		c.kmsKeyID = keyID
+	}
+}
+
+// WithSSEC encrypts the object with a caller-supplied SSE-C key. The same key must be passed
+// again on any subsequent Download, DownloadStream, or Stat call to read the object back, and
+// on Copy (via WithSourceSSEC) if the object is later used as a copy source.
+func WithSSEC(key []byte) EncryptionOption {
+	return func(c *encryptionConfig) {
+		c.sseCustomerKey = key
+	}
+}
+
+// WithSourceSSEC supplies the SSE-C key needed to read a Copy source object that was itself
+// uploaded with WithSSEC. It has no effect on any call other than Copy.
+func WithSourceSSEC(key []byte) EncryptionOption {
+	return func(c *encryptionConfig) {
+		c.sourceSSECustomerKey = key
+	}
+}
+
+func applyEncryptionOptions(opts []EncryptionOption) *encryptionConfig {
+	c := &encryptionConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *encryptionConfig) applyToPut(input *s3.PutObjectInput) {
+	if len(c.sseCustomerKey) > 0 {
+		alg, key, keyMD5 := sseCustomerHeaders(c.sseCustomerKey)
+		input.SSECustomerAlgorithm = aws.String(alg)
+		input.SSECustomerKey = aws.String(key)
+		input.SSECustomerKeyMD5 = aws.String(keyMD5)
+		return
+	}
+	if c.algorithm == "" {
+		return
+	}
+	input.ServerSideEncryption = aws.String(c.algorithm)
+	if c.algorithm == s3.ServerSideEncryptionAwsKms && c.kmsKeyID != "" {
+		input.SSEKMSKeyId = aws.String(c.kmsKeyID)
+	}
+}
+
+// applyToCopy sets the destination-side encryption of a CopyObjectInput. See applyToCopySource
+// for the source-side SSE-C headers needed to read an encrypted copy source.
+func (c *encryptionConfig) applyToCopy(input *s3.CopyObjectInput) {
+	if len(c.sseCustomerKey) > 0 {
+		alg, key, keyMD5 := sseCustomerHeaders(c.sseCustomerKey)
+		input.SSECustomerAlgorithm = aws.String(alg)
+		input.SSECustomerKey = aws.String(key)
+		input.SSECustomerKeyMD5 = aws.String(keyMD5)
+		return
+	}
+	if c.algorithm == "" {
+		return
+	}
+	input.ServerSideEncryption = aws.String(c.algorithm)
+	if c.algorithm == s3.ServerSideEncryptionAwsKms && c.kmsKeyID != "" {
+		input.SSEKMSKeyId = aws.String(c.kmsKeyID)
+	}
+}
+
+// applyToCopySource sets the CopySourceSSECustomerAlgorithm/Key/KeyMD5 headers S3 requires to
+// read a source object that was uploaded with WithSSEC, distinct from the destination-side
+// encryption applyToCopy sets.
+func (c *encryptionConfig) applyToCopySource(input *s3.CopyObjectInput) {
+	if len(c.sourceSSECustomerKey) == 0 {
+		return
+	}
+	alg, key, keyMD5 := sseCustomerHeaders(c.sourceSSECustomerKey)
+	input.CopySourceSSECustomerAlgorithm = aws.String(alg)
+	input.CopySourceSSECustomerKey = aws.String(key)
+	input.CopySourceSSECustomerKeyMD5 = aws.String(keyMD5)
+}
+
+// applyToGet sets the SSECustomerAlgorithm/Key/KeyMD5 headers S3 requires to read back an
+// object that was uploaded with WithSSEC.
+func (c *encryptionConfig) applyToGet(input *s3.GetObjectInput) {
+	if len(c.sseCustomerKey) == 0 {
+		return
+	}
+	alg, key, keyMD5 := sseCustomerHeaders(c.sseCustomerKey)
+	input.SSECustomerAlgorithm = aws.String(alg)
+	input.SSECustomerKey = aws.String(key)
+	input.SSECustomerKeyMD5 = aws.String(keyMD5)
+}
+
+// applyToHead sets the SSECustomerAlgorithm/Key/KeyMD5 headers S3 requires to Stat an object
+// that was uploaded with WithSSEC.
+func (c *encryptionConfig) applyToHead(input *s3.HeadObjectInput) {
+	if len(c.sseCustomerKey) == 0 {
+		return
+	}
+	alg, key, keyMD5 := sseCustomerHeaders(c.sseCustomerKey)
+	input.SSECustomerAlgorithm = aws.String(alg)
+	input.SSECustomerKey = aws.String(key)
+	input.SSECustomerKeyMD5 = aws.String(keyMD5)
+}
+
+func (c *encryptionConfig) applyToUploadInput(input *s3manager.UploadInput) {
+	if len(c.sseCustomerKey) > 0 {
+		alg, key, keyMD5 := sseCustomerHeaders(c.sseCustomerKey)
+		input.SSECustomerAlgorithm = aws.String(alg)
+		input.SSECustomerKey = aws.String(key)
+		input.SSECustomerKeyMD5 = aws.String(keyMD5)
+		return
+	}
+	if c.algorithm == "" {
+		return
+	}
+	input.ServerSideEncryption = aws.String(c.algorithm)
+	if c.algorithm == s3.ServerSideEncryptionAwsKms && c.kmsKeyID != "" {
+		input.SSEKMSKeyId = aws.String(c.kmsKeyID)
+	}
+}
+
+// WithUploadEncryption applies the given EncryptionOptions to an UploadStream call, letting
+// streamed uploads use the same SSE-S3/SSE-KMS/SSE-C choices as Upload and Copy.
+func WithUploadEncryption(opts ...EncryptionOption) UploadOption {
+	cfg := applyEncryptionOptions(opts)
+	return func(p *uploadParams) {
+		cfg.applyToUploadInput(p.input)
+	}
+}
+
+// ObjectInfo describes the metadata of a stored object, as returned by Stat.
+type ObjectInfo struct {
+	ETag                 string
+	ContentType          string
+	ContentLength        int64
+	ServerSideEncryption string
+	SSEKMSKeyID          string
+}
+
+// Stat returns metadata about an object without downloading its body, similar to the
+// object-inspection loop in the aws-sdk-go listBucketObjects example. Pass the same
+// EncryptionOption used to Upload an SSE-C object (e.g. WithSSEC) or S3 will reject the HEAD
+// with a 400. See ClientCtx.Stat, which this delegates to.
+func (s3C s3Client) Stat(p Path, opts ...EncryptionOption) (ObjectInfo, error) {
+	return s3ClientCtx(s3C).Stat(context.Background(), p, opts...)
+}
+
+// Stat returns metadata about an object without downloading its body. See Client.Stat.
+func (s3C s3ClientCtx) Stat(ctx context.Context, p Path, opts ...EncryptionOption) (ObjectInfo, error) {
+	input := &s3.HeadObjectInput{
+		Bucket: aws.String(p.Bucket),
+		Key:    aws.String(p.Key),
+	}
+	applyEncryptionOptions(opts).applyToHead(input)
+
+	resp, err := s3C.client.HeadObjectWithContext(ctx, input)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	info := ObjectInfo{}
+	if resp.ETag != nil {
+		info.ETag = *resp.ETag
+	}
+	if resp.ContentType != nil {
+		info.ContentType = *resp.ContentType
+	}
+	if resp.ContentLength != nil {
+		info.ContentLength = *resp.ContentLength
+	}
+	if resp.ServerSideEncryption != nil {
+		info.ServerSideEncryption = *resp.ServerSideEncryption
+	}
+	if resp.SSEKMSKeyId != nil {
+		info.SSEKMSKeyID = *resp.SSEKMSKeyId
+	}
+	return info, nil
+}
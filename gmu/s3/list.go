@@ -0,0 +1,125 @@
+package s3
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// ListOptions tunes how ListPaged walks objects under a Path, for callers that need finer
+// control than List/ListAll's defaults, such as large-scale sync or delete workflows.
+type ListOptions struct {
+	// MaxKeys caps the number of keys requested per underlying page. Zero uses the S3 default
+	// (1000).
+	MaxKeys int64
+	// StartAfter resumes listing after this key, letting callers paginate across calls of
+	// their own rather than consuming the whole list at once.
+	StartAfter string
+	// Recursive lists every key under the prefix instead of stopping at the next "/"
+	// delimiter, i.e. it descends into "subdirectories" rather than treating them as opaque
+	// common prefixes the way List does.
+	Recursive bool
+}
+
+// ListAll returns every Path under p, following continuation tokens via ListObjectsV2Pages so
+// callers aren't silently truncated to the first 1000 keys the way List is. See ClientCtx.ListAll
+// for the context-aware implementation this delegates to.
+func (s3C s3Client) ListAll(p Path) ([]Path, error) {
+	return s3ClientCtx(s3C).ListAll(context.Background(), p)
+}
+
+// ListPaged returns every Path under p matching opts, following continuation tokens so large
+// buckets aren't silently truncated to a single page of results. See ClientCtx.ListPaged for the
+// context-aware implementation this delegates to.
+func (s3C s3Client) ListPaged(p Path, opts ListOptions) ([]Path, error) {
+	return s3ClientCtx(s3C).ListPaged(context.Background(), p, opts)
+}
+
+// Walk recursively descends p, streaming every Path found on the returned channel rather than
+// holding all keys in memory the way ListAll does. See ClientCtx.Walk, which this delegates to,
+// for the channel-closing and cancellation semantics.
+func (s3C s3Client) Walk(ctx context.Context, p Path) (<-chan Path, <-chan error) {
+	return s3ClientCtx(s3C).Walk(ctx, p)
+}
+
+// ListAll returns every Path under p, following continuation tokens via ListObjectsV2PagesWithContext
+// so callers aren't silently truncated to the first 1000 keys the way List is.
+func (s3C s3ClientCtx) ListAll(ctx context.Context, p Path) ([]Path, error) {
+	return s3C.ListPaged(ctx, p, ListOptions{Recursive: true})
+}
+
+// ListPaged returns every Path under p matching opts, following continuation tokens so large
+// buckets aren't silently truncated to a single page of results.
+func (s3C s3ClientCtx) ListPaged(ctx context.Context, p Path, opts ListOptions) ([]Path, error) {
+	input := s3C.listPagedInput(p, opts)
+
+	var paths []Path
+	err := s3C.client.ListObjectsV2PagesWithContext(ctx, input, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, content := range page.Contents {
+			paths = append(paths, Path{Bucket: p.Bucket, Key: *content.Key})
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
+func (s3C s3ClientCtx) listPagedInput(p Path, opts ListOptions) *s3.ListObjectsV2Input {
+	input := &s3.ListObjectsV2Input{
+		Bucket: aws.String(p.Bucket),
+		Prefix: aws.String(listPrefix(p.Key)),
+	}
+	if !opts.Recursive {
+		input.Delimiter = aws.String("/")
+	}
+	if opts.MaxKeys > 0 {
+		input.MaxKeys = aws.Int64(opts.MaxKeys)
+	}
+	if opts.StartAfter != "" {
+		input.StartAfter = aws.String(opts.StartAfter)
+	}
+	return input
+}
+
+// Walk recursively descends p, streaming every Path found on the returned channel rather than
+// holding all keys in memory the way ListAll does. Both channels are closed once the walk
+// completes or ctx is cancelled; callers should drain the error channel after the Path channel
+// closes to learn whether the walk finished cleanly.
+func (s3C s3ClientCtx) Walk(ctx context.Context, p Path) (<-chan Path, <-chan error) {
+	paths := make(chan Path)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(paths)
+		defer close(errs)
+
+		input := s3C.listPagedInput(p, ListOptions{Recursive: true})
+		cancelled := false
+		err := s3C.client.ListObjectsV2PagesWithContext(ctx, input, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+			for _, content := range page.Contents {
+				select {
+				case paths <- Path{Bucket: p.Bucket, Key: *content.Key}:
+				case <-ctx.Done():
+					cancelled = true
+					return false
+				}
+			}
+			return true
+		})
+		// Pagination.Err() only reflects request errors, not an early `false` return from the
+		// page callback, so a cancellation would otherwise close errs having sent nothing,
+		// indistinguishable from a walk that ran to completion.
+		if cancelled {
+			errs <- ctx.Err()
+			return
+		}
+		if err != nil {
+			errs <- err
+		}
+	}()
+
+	return paths, errs
+}
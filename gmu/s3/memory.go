@@ -0,0 +1,301 @@
+package s3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// ErrNotSupportedByMemoryClient is returned by memoryClient methods that have no meaningful
+// in-memory equivalent, such as presigning.
+var ErrNotSupportedByMemoryClient = errors.New("operation not supported by the in-memory s3 backend")
+
+// memoryClient is a Backend implementation that keeps all buckets and objects in a process-local
+// map. It has no network or AWS dependency, so it's cheap to spin up per-test, at the cost of
+// not exercising real S3 request signing, XML encoding, or pagination the way NewLocalClient does.
+type memoryClient struct {
+	mu      sync.Mutex
+	buckets map[string]map[string]string // bucket -> tags
+	objects map[Path][]byte
+}
+
+// NewInMemoryClient returns a Backend backed entirely by an in-process map, for
+// zero-dependency unit tests that don't need a real (or fake) S3 endpoint.
+func NewInMemoryClient() Client {
+	return &memoryClient{
+		buckets: map[string]map[string]string{},
+		objects: map[Path][]byte{},
+	}
+}
+
+func (m *memoryClient) ListDirectories(p Path) ([]Path, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	prefix := listPrefix(p.Key)
+
+	seen := map[string]bool{}
+	var dirs []Path
+	for key := range m.objects {
+		if key.Bucket != p.Bucket || !strings.HasPrefix(key.Key, prefix) {
+			continue
+		}
+		rest := strings.TrimPrefix(key.Key, prefix)
+		idx := strings.Index(rest, "/")
+		if idx < 0 {
+			continue
+		}
+		dir := prefix + rest[:idx+1]
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		dirs = append(dirs, Path{Bucket: p.Bucket, Key: dir})
+	}
+	return dirs, nil
+}
+
+func (m *memoryClient) List(p Path) ([]Path, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	prefix := listPrefix(p.Key)
+
+	var paths []Path
+	for key := range m.objects {
+		if key.Bucket != p.Bucket || !strings.HasPrefix(key.Key, prefix) {
+			continue
+		}
+		if strings.Contains(strings.TrimPrefix(key.Key, prefix), "/") {
+			continue
+		}
+		paths = append(paths, key)
+	}
+	return paths, nil
+}
+
+func (m *memoryClient) ListAll(p Path) ([]Path, error) {
+	return m.ListPaged(p, ListOptions{Recursive: true})
+}
+
+func (m *memoryClient) ListPaged(p Path, opts ListOptions) ([]Path, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	prefix := listPrefix(p.Key)
+
+	var paths []Path
+	for key := range m.objects {
+		if key.Bucket != p.Bucket || !strings.HasPrefix(key.Key, prefix) {
+			continue
+		}
+		if !opts.Recursive && strings.Contains(strings.TrimPrefix(key.Key, prefix), "/") {
+			continue
+		}
+		if opts.StartAfter != "" && key.Key <= opts.StartAfter {
+			continue
+		}
+		paths = append(paths, key)
+	}
+	// MaxKeys only sizes the pages of a real listing, which are all followed; this backend
+	// has no pages, so every matching key is returned regardless of MaxKeys.
+	return paths, nil
+}
+
+// Walk streams every Path under p on the returned channel. See Client.Walk.
+func (m *memoryClient) Walk(ctx context.Context, p Path) (<-chan Path, <-chan error) {
+	paths := make(chan Path)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(paths)
+		defer close(errs)
+
+		all, err := m.ListAll(p)
+		if err != nil {
+			errs <- err
+			return
+		}
+		for _, path := range all {
+			select {
+			case paths <- path:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	return paths, errs
+}
+
+func (m *memoryClient) Download(p Path, opts ...EncryptionOption) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b, ok := m.objects[p]
+	if !ok {
+		return nil, fmt.Errorf("memory backend: object not found: %s", p.ToURI())
+	}
+	return append([]byte{}, b...), nil
+}
+
+func (m *memoryClient) DownloadInFile(p Path, f *os.File) (int64, error) {
+	b, err := m.Download(p)
+	if err != nil {
+		return 0, err
+	}
+	n, err := f.Write(b)
+	return int64(n), err
+}
+
+func (m *memoryClient) DownloadStream(p Path, w io.WriterAt, opts ...EncryptionOption) (int64, error) {
+	b, err := m.Download(p, opts...)
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.WriteAt(b, 0)
+	return int64(n), err
+}
+
+func (m *memoryClient) PresignGet(p Path, expiry time.Duration) (string, error) {
+	return "", ErrNotSupportedByMemoryClient
+}
+
+func (m *memoryClient) PresignPut(p Path, expiry time.Duration, opts ...PresignOption) (string, error) {
+	return "", ErrNotSupportedByMemoryClient
+}
+
+func (m *memoryClient) Copy(source Path, target Path, opts ...EncryptionOption) error {
+	b, err := m.Download(source)
+	if err != nil {
+		return err
+	}
+	return m.Upload(b, target, opts...)
+}
+
+func (m *memoryClient) Upload(b []byte, p Path, opts ...EncryptionOption) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// The in-memory backend never serves objects over the network, so encryption options are
+	// accepted for interface compatibility but otherwise have no effect.
+	m.objects[p] = append([]byte{}, b...)
+	return nil
+}
+
+func (m *memoryClient) UploadStream(r io.Reader, p Path, opts ...UploadOption) error {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return m.Upload(b, p)
+}
+
+func (m *memoryClient) AbortMultipartUpload(p Path, uploadID string) error {
+	// The in-memory backend never leaves orphaned parts behind, so there's nothing to abort.
+	return nil
+}
+
+func (m *memoryClient) DeleteRecursive(p Path) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	prefix := listPrefix(p.Key)
+	for key := range m.objects {
+		if key.Bucket == p.Bucket && strings.HasPrefix(key.Key, prefix) {
+			delete(m.objects, key)
+		}
+	}
+	return nil
+}
+
+func (m *memoryClient) DeleteBucket(name string, log logr.Logger) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key := range m.objects {
+		if key.Bucket == name {
+			delete(m.objects, key)
+		}
+	}
+	delete(m.buckets, name)
+	log.Info("removed all object(s) from bucket for deletion", "bucket", name)
+	return nil
+}
+
+func (m *memoryClient) DeleteObject(p Path) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.objects, p)
+	return nil
+}
+
+func (m *memoryClient) Exists(p Path) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, ok := m.objects[p]
+	return ok
+}
+
+func (m *memoryClient) CreateBucket(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.buckets[name]; ok {
+		return fmt.Errorf("memory backend: bucket already exists: %s", name)
+	}
+	m.buckets[name] = map[string]string{}
+	return nil
+}
+
+func (m *memoryClient) AddBucketTag(bucketName string, tags map[string]string, overwrite bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	existing, ok := m.buckets[bucketName]
+	if !ok {
+		existing = map[string]string{}
+	}
+	for k, v := range tags {
+		if _, ok := existing[k]; ok && !overwrite {
+			continue
+		}
+		existing[k] = v
+	}
+	m.buckets[bucketName] = existing
+	return nil
+}
+
+func (m *memoryClient) BlockBucketPublicAccess(name string) error {
+	// The in-memory backend has no notion of public access, so this is a no-op kept for
+	// interface compatibility.
+	return nil
+}
+
+func (m *memoryClient) Stat(p Path, opts ...EncryptionOption) (ObjectInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b, ok := m.objects[p]
+	if !ok {
+		return ObjectInfo{}, fmt.Errorf("memory backend: object not found: %s", p.ToURI())
+	}
+	return ObjectInfo{ContentLength: int64(len(b))}, nil
+}
+
+func (m *memoryClient) BucketServerSideEncryption(bucket string) error {
+	// The in-memory backend never serves objects over the network, so there's no encryption
+	// at rest to configure. Kept as a no-op for interface compatibility.
+	return nil
+}
@@ -0,0 +1,84 @@
+package s3
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// PresignOption customizes the parameters baked into a presigned PUT URL, so that the
+// eventual PUT request is constrained to match what was signed.
+type PresignOption func(*s3.PutObjectInput)
+
+// WithPresignContentType pins the Content-Type header that the presigned PUT request must use.
+func WithPresignContentType(contentType string) PresignOption {
+	return func(i *s3.PutObjectInput) {
+		i.ContentType = aws.String(contentType)
+	}
+}
+
+// WithPresignContentDisposition pins the Content-Disposition header that the presigned PUT
+// request must use.
+func WithPresignContentDisposition(disposition string) PresignOption {
+	return func(i *s3.PutObjectInput) {
+		i.ContentDisposition = aws.String(disposition)
+	}
+}
+
+// WithPresignACL pins the canned ACL that the presigned PUT request must use.
+func WithPresignACL(acl string) PresignOption {
+	return func(i *s3.PutObjectInput) {
+		i.ACL = aws.String(acl)
+	}
+}
+
+// WithPresignMetadata pins x-amz-meta-* headers that the presigned PUT request must include.
+func WithPresignMetadata(metadata map[string]string) PresignOption {
+	return func(i *s3.PutObjectInput) {
+		i.Metadata = aws.StringMap(metadata)
+	}
+}
+
+// PresignGet returns a URL that allows an anonymous GET of p's contents, valid for expiry.
+// This lets callers hand out short-lived download links without proxying bytes themselves. See
+// ClientCtx.PresignGet, which this delegates to.
+func (s3C s3Client) PresignGet(p Path, expiry time.Duration) (string, error) {
+	return s3ClientCtx(s3C).PresignGet(context.Background(), p, expiry)
+}
+
+// PresignPut returns a URL that allows an anonymous PUT of content to p, valid for expiry.
+// opts can pin the ContentType, ContentDisposition, ACL, or metadata that the eventual PUT
+// request must match, so that e.g. a browser upload can be signed without exposing credentials.
+// See ClientCtx.PresignPut, which this delegates to.
+func (s3C s3Client) PresignPut(p Path, expiry time.Duration, opts ...PresignOption) (string, error) {
+	return s3ClientCtx(s3C).PresignPut(context.Background(), p, expiry, opts...)
+}
+
+// PresignGet returns a URL that allows an anonymous GET of p's contents, valid for expiry. See
+// Client.PresignGet. Signing a request is a local operation with no network round trip, so ctx
+// is accepted only for parity with the rest of ClientCtx and isn't otherwise used.
+func (s3C s3ClientCtx) PresignGet(ctx context.Context, p Path, expiry time.Duration) (string, error) {
+	req, _ := s3C.client.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(p.Bucket),
+		Key:    aws.String(p.Key),
+	})
+	return req.Presign(expiry)
+}
+
+// PresignPut returns a URL that allows an anonymous PUT of content to p, valid for expiry. See
+// Client.PresignPut. Signing a request is a local operation with no network round trip, so ctx
+// is accepted only for parity with the rest of ClientCtx and isn't otherwise used.
+func (s3C s3ClientCtx) PresignPut(ctx context.Context, p Path, expiry time.Duration, opts ...PresignOption) (string, error) {
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(p.Bucket),
+		Key:    aws.String(p.Key),
+	}
+	for _, opt := range opts {
+		opt(input)
+	}
+
+	req, _ := s3C.client.PutObjectRequest(input)
+	return req.Presign(expiry)
+}
@@ -1,22 +1,21 @@
 package s3
 
 import (
-	"bytes"
+	"context"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"net/url"
 	"os"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
-	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 )
 
 const (
@@ -82,10 +81,18 @@ func FromURI(uri string) (Path, error) {
 type Client interface {
 	ListDirectories(p Path) ([]Path, error)
 	List(p Path) ([]Path, error)
-	Download(p Path) ([]byte, error)
+	ListAll(p Path) ([]Path, error)
+	ListPaged(p Path, opts ListOptions) ([]Path, error)
+	Walk(ctx context.Context, p Path) (<-chan Path, <-chan error)
+	Download(p Path, opts ...EncryptionOption) ([]byte, error)
 	DownloadInFile(p Path, f *os.File) (int64, error)
-	Copy(source Path, target Path) error
-	Upload(b []byte, p Path) error
+	DownloadStream(p Path, w io.WriterAt, opts ...EncryptionOption) (int64, error)
+	PresignGet(p Path, expiry time.Duration) (string, error)
+	PresignPut(p Path, expiry time.Duration, opts ...PresignOption) (string, error)
+	Copy(source Path, target Path, opts ...EncryptionOption) error
+	Upload(b []byte, p Path, opts ...EncryptionOption) error
+	UploadStream(r io.Reader, p Path, opts ...UploadOption) error
+	AbortMultipartUpload(p Path, uploadID string) error
 	DeleteRecursive(p Path) error
 	DeleteBucket(name string, log logr.Logger) error
 	DeleteObject(p Path) error
@@ -94,9 +101,13 @@ type Client interface {
 	AddBucketTag(bucketName string, tags map[string]string, overwrite bool) error
 	BlockBucketPublicAccess(name string) error
 	BucketServerSideEncryption(bucket string) error
+	Stat(p Path, opts ...EncryptionOption) (ObjectInfo, error)
 }
 
-// s3Client is for using the s3 client to access the resources
+// s3Client is for using the s3 client to access the resources. Every method is implemented by
+// converting itself to the field-identical s3ClientCtx and calling its context-aware
+// counterpart with context.Background() (Walk, which already takes a ctx, passes it through), so
+// Client and ClientCtx can never drift apart on method coverage; see ctx.go.
 type s3Client struct {
 	client  *s3.S3
 	session *session.Session
@@ -157,19 +168,15 @@ func NewClient(options ...ClientOption) (Client, error) {
 	return &s3, nil
 }
 
-func (s3C s3Client) listBase(p Path) (*s3.ListObjectsV2Output, error) {
-	awsKey := aws.String(p.Key + "/")
-	if p.Key == "" {
-		awsKey = aws.String(p.Key)
+// listPrefix returns the S3 prefix to list under key, appending the "/" delimiter unless key is
+// empty (an empty key lists the whole bucket, so it must stay un-prefixed rather than becoming
+// "/"). Every listing path - s3Client, s3ClientCtx, and memoryClient alike - derives its prefix
+// from this helper so a fake backend lists exactly the same keys a real one would.
+func listPrefix(key string) string {
+	if key == "" {
+		return ""
 	}
-
-	resp, err := s3C.client.ListObjectsV2(&s3.ListObjectsV2Input{
-		Bucket:    aws.String(p.Bucket),
-		Prefix:    awsKey,
-		Delimiter: aws.String("/"),
-	})
-
-	return resp, err
+	return key + "/"
 }
 
 // ListDirectories gets the subdirectories of this path
@@ -178,233 +185,89 @@ func (s3C s3Client) listBase(p Path) (*s3.ListObjectsV2Output, error) {
 // web ui displays "directories" within a bucket, by treating slashes as directory delimiters
 // and only displaying the "common prefixes" (read: directory names) in the namespace currently
 // being displayed. The idea of common prefixes is leveraged in this function, ListDirectories.
+// See ClientCtx.ListDirectories, which this delegates to.
 func (s3C s3Client) ListDirectories(p Path) ([]Path, error) {
-	resp, err := s3C.listBase(p)
-	if err != nil {
-		return []Path{}, err
-	}
-
-	s3Paths := make([]Path, len(resp.CommonPrefixes))
-	for i, cp := range resp.CommonPrefixes {
-		s3Paths[i] = Path{Bucket: p.Bucket, Key: *cp.Prefix}
-	}
-	return s3Paths, nil
+	return s3ClientCtx(s3C).ListDirectories(context.Background(), p)
 }
 
-// List returns a list of Paths under the passed Path
+// List returns a list of Paths under the passed Path. See ClientCtx.List, which this delegates
+// to.
 func (s3C s3Client) List(p Path) ([]Path, error) {
-	resp, err := s3C.listBase(p)
-	if err != nil {
-		return []Path{}, err
-	}
-
-	s3Paths := make([]Path, len(resp.Contents))
-	for i, content := range resp.Contents {
-		s3Paths[i] = Path{Bucket: p.Bucket, Key: *content.Key}
-	}
-	return s3Paths, nil
+	return s3ClientCtx(s3C).List(context.Background(), p)
 }
 
-// Download gets bytes from S3 Path
-func (s3C s3Client) Download(p Path) ([]byte, error) {
-	resp, err := s3C.client.GetObject(&s3.GetObjectInput{
-		Bucket: aws.String(p.Bucket),
-		Key:    aws.String(p.Key),
-	})
-	if err != nil {
-		return []byte{}, err
-	}
-	bytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return []byte{}, err
-	}
-	return bytes, nil
+// Download gets bytes from S3 Path. Pass the same EncryptionOption used to Upload an SSE-C
+// object (e.g. WithSSEC) or S3 will reject the GET with a 400. See ClientCtx.Download, which
+// this delegates to.
+func (s3C s3Client) Download(p Path, opts ...EncryptionOption) ([]byte, error) {
+	return s3ClientCtx(s3C).Download(context.Background(), p, opts...)
 }
 
-// DownloadInFile download s3 path object to provided file
+// DownloadInFile download s3 path object to provided file. See ClientCtx.DownloadInFile, which
+// this delegates to.
 func (s3C s3Client) DownloadInFile(p Path, f *os.File) (int64, error) {
-	downloader := s3manager.NewDownloader(s3C.session)
-
-	return downloader.Download(f,
-		&s3.GetObjectInput{
-			Bucket: aws.String(p.Bucket),
-			Key:    aws.String(p.Key),
-		})
+	return s3ClientCtx(s3C).DownloadInFile(context.Background(), p, f)
 }
 
-// Copy copies resources from source Path to target Path
-func (s3C s3Client) Copy(source Path, target Path) error {
-	_, err := s3C.client.CopyObject(&s3.CopyObjectInput{
-		Bucket:     aws.String(target.Bucket),
-		Key:        aws.String(target.Key),
-		CopySource: aws.String(source.Join()),
-	})
-	return err
+// Copy copies resources from source Path to target Path. opts can be used to set the
+// server-side encryption of the copied object, overriding the source object's encryption, and
+// to pass the SSE-C key of the source object (via WithSourceSSEC) when it needs decrypting to
+// be read, or S3 will reject the copy with a 400. See ClientCtx.Copy, which this delegates to.
+func (s3C s3Client) Copy(source Path, target Path, opts ...EncryptionOption) error {
+	return s3ClientCtx(s3C).Copy(context.Background(), source, target, opts...)
 }
 
-// Upload writes byte array to S3 Path location
-func (s3C s3Client) Upload(b []byte, p Path) error {
-	_, err := s3C.client.PutObject(&s3.PutObjectInput{
-		Bucket: aws.String(p.Bucket),
-		Key:    aws.String(p.Key),
-		Body:   bytes.NewReader(b),
-	})
-	return err
+// Upload writes byte array to S3 Path location. opts can be used to set the server-side
+// encryption of the uploaded object, such as SSE-KMS or a caller-supplied SSE-C key. See
+// ClientCtx.Upload, which this delegates to.
+func (s3C s3Client) Upload(b []byte, p Path, opts ...EncryptionOption) error {
+	return s3ClientCtx(s3C).Upload(context.Background(), b, p, opts...)
 }
 
-// DeleteRecursive removes resources of S3 Bucket recursively
+// DeleteRecursive removes resources of S3 Bucket recursively. See ClientCtx.DeleteRecursive,
+// which this delegates to.
 func (s3C s3Client) DeleteRecursive(p Path) error {
-	// Code following https://github.com/awsdocs/aws-doc-sdk-examples/blob/master/go/example_code/s3/s3_delete_objects.go
-
-	// Setup BatchDeleteIterator to iterate through a list of objects.
-	iter := s3manager.NewDeleteListIterator(s3C.client, &s3.ListObjectsInput{
-		Bucket:    aws.String(p.Bucket),
-		Delimiter: aws.String("/"),
-		Prefix:    aws.String(p.Key + "/"),
-	})
-
-	// Traverse iterator deleting each object
-	return s3manager.NewBatchDeleteWithClient(s3C.client).Delete(aws.BackgroundContext(), iter)
+	return s3ClientCtx(s3C).DeleteRecursive(context.Background(), p)
 }
 
-// DeleteBucket removes the S3 Bucket
+// DeleteBucket removes the S3 Bucket. See ClientCtx.DeleteBucket, which this delegates to.
 func (s3C s3Client) DeleteBucket(name string, log logr.Logger) error {
-	input := &s3.DeleteBucketInput{
-		Bucket: aws.String(name),
-	}
-
-	// Setup BatchDeleteIterator to iterate through a list of objects.
-	iter := s3manager.NewDeleteListIterator(s3C.client, &s3.ListObjectsInput{
-		Bucket: aws.String(name),
-	})
-
-	// Traverse iterator deleting each object
-	if err := s3manager.NewBatchDeleteWithClient(s3C.client).Delete(aws.BackgroundContext(), iter); err != nil {
-		log.Error(err, "unable to remove objects from bucket for deletion", "bucket", name)
-		return err
-	}
-
-	log.Info("removed all object(s) from bucket for deletion", "bucket", name)
-
-	_, err := s3C.client.DeleteBucket(input)
-	return err
+	return s3ClientCtx(s3C).DeleteBucket(context.Background(), name, log)
 }
 
-// DeleteObject removes a single S3 object
+// DeleteObject removes a single S3 object. See ClientCtx.DeleteObject, which this delegates to.
 func (s3C s3Client) DeleteObject(p Path) error {
-	_, err := s3C.client.DeleteObject(&s3.DeleteObjectInput{
-		Bucket: aws.String(p.Bucket),
-		Key:    aws.String(p.Key),
-	})
-	return err
+	return s3ClientCtx(s3C).DeleteObject(context.Background(), p)
 }
 
-// Exists returns True if the object metatdata exists false otherwise
+// Exists returns True if the object metatdata exists false otherwise. See ClientCtx.Exists,
+// which this delegates to.
 func (s3C s3Client) Exists(p Path) bool {
-	_, err := s3C.client.HeadObject(&s3.HeadObjectInput{
-		Bucket: aws.String(p.Bucket),
-		Key:    aws.String(p.Key),
-	})
-
-	return err == nil
+	return s3ClientCtx(s3C).Exists(context.Background(), p)
 }
 
-// CreateBucket create a new bucket. This will raise an BucketAlreadyExitsError if the bucket is owned by someone else
+// CreateBucket create a new bucket. This will raise an BucketAlreadyExitsError if the bucket is
+// owned by someone else. See ClientCtx.CreateBucket, which this delegates to.
 func (s3C s3Client) CreateBucket(name string) error {
-	_, err := s3C.client.CreateBucket(&s3.CreateBucketInput{
-		Bucket: aws.String(name),
-	})
-	return err
+	return s3ClientCtx(s3C).CreateBucket(context.Background(), name)
 }
 
-// BlockBucketPublicAccess blocks public access for a named bucket
+// BlockBucketPublicAccess blocks public access for a named bucket. See
+// ClientCtx.BlockBucketPublicAccess, which this delegates to.
 func (s3C s3Client) BlockBucketPublicAccess(name string) error {
-	_, err := s3C.client.PutPublicAccessBlock(&s3.PutPublicAccessBlockInput{
-		Bucket: aws.String(name),
-		PublicAccessBlockConfiguration: &s3.PublicAccessBlockConfiguration{
-			BlockPublicAcls:       aws.Bool(true),
-			BlockPublicPolicy:     aws.Bool(true),
-			IgnorePublicAcls:      aws.Bool(true),
-			RestrictPublicBuckets: aws.Bool(true),
-		},
-	})
-	if err != nil {
-		return err
-	}
-	return nil
+	return s3ClientCtx(s3C).BlockBucketPublicAccess(context.Background(), name)
 }
 
 // AddBucketTag adds a tag to the bucket metadata
 // tags accept any number of tags in a map format
 // overwrite allows you to overwrite previous tags
+// See ClientCtx.AddBucketTag, which this delegates to.
 func (s3C s3Client) AddBucketTag(bucketName string, tags map[string]string, overwrite bool) error {
-	if getTagOutput, err := s3C.client.GetBucketTagging(
-		&s3.GetBucketTaggingInput{
-			Bucket: aws.String(bucketName),
-		},
-	); err != nil {
-		// Handling special error code
-		// * Error code: NoSuchTagSetError Description: There is no tag set associated with the bucket.
-		if awsErr, ok := err.(awserr.Error); ok {
-			errCode := awsErr.Code()
-			switch errCode {
-			case "NoSuchTagSet":
-				break
-			default:
-				return awsErr
-			}
-		} else {
-			return err
-		}
-	} else {
-		for _, tag := range getTagOutput.TagSet {
-			// Skip tags present in the list
-			if tag.Key == nil || tag.Value == nil {
-				// Defensive measure, prevent nil tags
-				continue
-			}
-			if _, ok := tags[*tag.Key]; ok && overwrite {
-				// overwrite
-				continue
-			}
-			tags[*tag.Key] = *tag.Value
-		}
-	}
-
-	tagSet := []*s3.Tag{}
-	for key, value := range tags {
-		tagSet = append(tagSet, &s3.Tag{
-			Key:   aws.String(key),
-			Value: aws.String(value),
-		})
-	}
-
-	_, err := s3C.client.PutBucketTagging(
-		&s3.PutBucketTaggingInput{
-			Bucket: aws.String(bucketName),
-			Tagging: &s3.Tagging{
-				TagSet: tagSet,
-			},
-		})
-	return err
+	return s3ClientCtx(s3C).AddBucketTag(context.Background(), bucketName, tags, overwrite)
 }
 
-// EncryptServerSideEncryption this will be used to encrypt using default awskms key
+// EncryptServerSideEncryption this will be used to encrypt using default awskms key. See
+// ClientCtx.BucketServerSideEncryption, which this delegates to.
 func (s3C s3Client) BucketServerSideEncryption(bucket string) error {
-	bucketKeyEnabled := true
-	// Encrypt with KMS by default
-	defEnc := &s3.ServerSideEncryptionByDefault{SSEAlgorithm: aws.String(s3.ServerSideEncryptionAwsKms)}
-	rule := &s3.ServerSideEncryptionRule{
-		ApplyServerSideEncryptionByDefault: defEnc,
-		BucketKeyEnabled:                   &bucketKeyEnabled,
-	}
-	rules := []*s3.ServerSideEncryptionRule{rule}
-	serverConfig := &s3.ServerSideEncryptionConfiguration{Rules: rules}
-	input := &s3.PutBucketEncryptionInput{Bucket: aws.String(bucket), ServerSideEncryptionConfiguration: serverConfig}
-
-	_, err := s3C.client.PutBucketEncryption(input)
-	if err != nil {
-		return err
-	}
-	return err
+	return s3ClientCtx(s3C).BucketServerSideEncryption(context.Background(), bucket)
 }
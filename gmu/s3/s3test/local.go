@@ -0,0 +1,58 @@
+// Package s3test provides gofakes3-backed fakes for gmu/s3, kept out of the main package so
+// that plain consumers of s3.Client don't pull in gofakes3/s3mem as a transitive dependency.
+package s3test
+
+import (
+	"net/http/httptest"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/johannesboyne/gofakes3"
+	"github.com/johannesboyne/gofakes3/backend/s3mem"
+
+	"github.com/PrimerAI/go-micro-utils-public/gmu/s3"
+)
+
+// LocalClient is a s3.Backend backed by an in-process gofakes3 server storing objects with
+// s3mem, reached over a real loopback HTTP connection. Unlike s3.NewInMemoryClient, it exercises
+// the full AWS SDK request path (SigV4 signing, XML (un)marshalling, pagination), mirroring how
+// gitlab-workhorse wires gofakes3+s3mem behind an httptest.Server for tests that want a real
+// S3-compatible endpoint without depending on an actual AWS account. Call Close when done.
+type LocalClient struct {
+	s3.Client
+	server *httptest.Server
+}
+
+// Close shuts down the underlying httptest.Server.
+func (l *LocalClient) Close() {
+	l.server.Close()
+}
+
+// Endpoint returns the loopback URL of the underlying gofakes3 server, so callers that need to
+// target it directly (e.g. to construct a second client against the same fake, such as
+// s3.NewClientCtx) don't have to reimplement the httptest.Server plumbing.
+func (l *LocalClient) Endpoint() string {
+	return l.server.URL
+}
+
+// NewLocalClient starts an in-process gofakes3 server and returns a s3.Backend that targets it
+// via session.NewSession, the same as s3.NewClient(s3.WithEndpoint(...)) would for a real
+// S3-compatible endpoint like minio. Additional options are applied after the defaults needed to
+// reach the fake server, so callers can still override region, credentials, etc.
+func NewLocalClient(options ...s3.ClientOption) (*LocalClient, error) {
+	server := httptest.NewServer(gofakes3.New(s3mem.New()).Server())
+
+	opts := append([]s3.ClientOption{
+		s3.WithEndpoint(server.URL),
+		s3.WithS3ForcePathStyle(true),
+		s3.WithDisableSSL(true),
+		s3.WithCredentials(credentials.NewStaticCredentials("FAKE_ACCESS_KEY", "FAKE_SECRET_KEY", "")),
+	}, options...)
+
+	client, err := s3.NewClient(opts...)
+	if err != nil {
+		server.Close()
+		return nil, err
+	}
+
+	return &LocalClient{Client: client, server: server}, nil
+}
@@ -0,0 +1,137 @@
+package s3
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// uploadParams bundles the s3manager.Uploader settings and the resulting UploadInput so that
+// UploadOptions can tune either the transfer mechanics or the request itself.
+type uploadParams struct {
+	uploader *s3manager.Uploader
+	input    *s3manager.UploadInput
+}
+
+// UploadOption configures the s3manager.Uploader used by UploadStream, such as part size and
+// concurrency for multipart uploads.
+type UploadOption func(*uploadParams)
+
+// WithPartSize sets the size in bytes of each part sent during a multipart upload. AWS
+// requires this to be at least s3manager.MinUploadPartSize (5MB).
+func WithPartSize(size int64) UploadOption {
+	return func(p *uploadParams) {
+		p.uploader.PartSize = size
+	}
+}
+
+// WithConcurrency sets the number of parts uploaded in parallel during a multipart upload.
+func WithConcurrency(concurrency int) UploadOption {
+	return func(p *uploadParams) {
+		p.uploader.Concurrency = concurrency
+	}
+}
+
+// WithLeavePartsOnError controls whether successfully uploaded parts are left in S3 after an
+// upload fails, so callers can resume the multipart upload using the UploadID reported in the
+// returned MultipartUploadFailure instead of starting over.
+func WithLeavePartsOnError(leave bool) UploadOption {
+	return func(p *uploadParams) {
+		p.uploader.LeavePartsOnError = leave
+	}
+}
+
+// MultipartUploadFailure wraps a failed multipart upload with its UploadID so callers can
+// resume or clean up (via AbortMultipartUpload) instead of losing track of orphaned parts.
+type MultipartUploadFailure struct {
+	UploadID string
+	Err      error
+}
+
+// Error implements the error interface.
+func (e *MultipartUploadFailure) Error() string {
+	return fmt.Sprintf("multipart upload %s failed: %v", e.UploadID, e.Err)
+}
+
+// Unwrap allows callers to use errors.As/errors.Is against the wrapped SDK error.
+func (e *MultipartUploadFailure) Unwrap() error {
+	return e.Err
+}
+
+// UploadStream writes the contents of r to the S3 Path p using s3manager.Uploader, which
+// splits large objects into concurrent multipart uploads rather than requiring the caller to
+// buffer the whole object, as Upload does. See ClientCtx.UploadStream, which this delegates to.
+func (s3C s3Client) UploadStream(r io.Reader, p Path, opts ...UploadOption) error {
+	return s3ClientCtx(s3C).UploadStream(context.Background(), r, p, opts...)
+}
+
+// DownloadStream writes the contents of the S3 Path p to w using s3manager.Downloader, which
+// fetches large objects concurrently in byte-range chunks rather than requiring the caller to
+// buffer the whole object, as Download does. w must support writing at arbitrary offsets
+// (e.g. *os.File) since chunks may complete out of order. Pass the same EncryptionOption used
+// to Upload an SSE-C object (e.g. WithSSEC) or S3 will reject the GET with a 400. See
+// ClientCtx.DownloadStream, which this delegates to.
+func (s3C s3Client) DownloadStream(p Path, w io.WriterAt, opts ...EncryptionOption) (int64, error) {
+	return s3ClientCtx(s3C).DownloadStream(context.Background(), p, w, opts...)
+}
+
+// AbortMultipartUpload cancels an in-progress multipart upload identified by uploadID, freeing
+// any parts already stored in S3. Use the UploadID from a MultipartUploadFailure returned by
+// UploadStream to clean up after a failed upload. See ClientCtx.AbortMultipartUpload, which this
+// delegates to.
+func (s3C s3Client) AbortMultipartUpload(p Path, uploadID string) error {
+	return s3ClientCtx(s3C).AbortMultipartUpload(context.Background(), p, uploadID)
+}
+
+// UploadStream writes the contents of r to the S3 Path p using s3manager.Uploader. See
+// Client.UploadStream.
+func (s3C s3ClientCtx) UploadStream(ctx context.Context, r io.Reader, p Path, opts ...UploadOption) error {
+	params := &uploadParams{
+		uploader: s3manager.NewUploaderWithClient(s3C.client),
+		input: &s3manager.UploadInput{
+			Bucket: aws.String(p.Bucket),
+			Key:    aws.String(p.Key),
+			Body:   r,
+		},
+	}
+	for _, opt := range opts {
+		opt(params)
+	}
+
+	_, err := params.uploader.UploadWithContext(ctx, params.input)
+	if err != nil {
+		if multiErr, ok := err.(s3manager.MultiUploadFailure); ok {
+			return &MultipartUploadFailure{UploadID: multiErr.UploadID(), Err: multiErr}
+		}
+		return err
+	}
+	return nil
+}
+
+// DownloadStream writes the contents of the S3 Path p to w using s3manager.Downloader. See
+// Client.DownloadStream.
+func (s3C s3ClientCtx) DownloadStream(ctx context.Context, p Path, w io.WriterAt, opts ...EncryptionOption) (int64, error) {
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(p.Bucket),
+		Key:    aws.String(p.Key),
+	}
+	applyEncryptionOptions(opts).applyToGet(input)
+
+	downloader := s3manager.NewDownloaderWithClient(s3C.client)
+	return downloader.DownloadWithContext(ctx, w, input)
+}
+
+// AbortMultipartUpload cancels an in-progress multipart upload identified by uploadID. See
+// Client.AbortMultipartUpload.
+func (s3C s3ClientCtx) AbortMultipartUpload(ctx context.Context, p Path, uploadID string) error {
+	_, err := s3C.client.AbortMultipartUploadWithContext(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(p.Bucket),
+		Key:      aws.String(p.Key),
+		UploadId: aws.String(uploadID),
+	})
+	return err
+}